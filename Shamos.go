@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"image"
 	"log"
-	"math"
 
 	"github.com/quasoft/dcel"
+	"github.com/tidwall/rtree"
 )
 
 type Voronoi struct {
@@ -17,6 +17,16 @@ type Voronoi struct {
 	ParabolaTree *Node
 	SweepLine    int // tracks the current position of the sweep line; updated when a new site is added.
 	DCEL         *dcel.DCEL
+	OuterFace    *dcel.Face // the unbounded face outside Bounds, created on demand by Clip
+
+	triangulation *Triangulation // dual Delaunay triangulation, recorded as circle events fire
+
+	siteOffsets map[int64]PointF // sub-pixel fraction NewFromPointsF rounded away per site, fed back into calcCircle
+
+	index         *rtree.RTree // bounding boxes of cell polygons, for SiteAt's narrowing step
+	centerIndex   *rtree.RTree // site centers, for NearestSite/KNearest
+	indexPolygons map[int64][]image.Point
+	indexSites    map[int64]*Site
 }
 
 func New(sites SiteSlice, bounds image.Rectangle) *Voronoi {
@@ -55,6 +65,12 @@ func (v *Voronoi) Reset() {
 	v.ParabolaTree = nil
 	v.SweepLine = 0
 	v.DCEL = dcel.NewDCEL()
+	v.OuterFace = nil
+	v.triangulation = nil
+	v.index = nil
+	v.centerIndex = nil
+	v.indexPolygons = nil
+	v.indexSites = nil
 }
 
 func (v *Voronoi) HandleNextEvent() {
@@ -64,7 +80,11 @@ func (v *Voronoi) HandleNextEvent() {
 
 	event := heap.Pop(&v.EventQueue).(*Event)
 
-	if event.Y < v.SweepLine {
+	// A strict < here would discard circle events that land a fraction
+	// of a pixel above the sweep line purely from float64-to-int
+	// rounding in calcCircle; EpsEquals2D-style tolerance treats those
+	// as coincident with the sweep line instead of dropping them.
+	if float64(event.Y) < float64(v.SweepLine)-sweepEpsilon {
 		log.Printf("Ignoring event with Y %d as it's above the sweep line (%d)\r\n", event.Y, v.SweepLine)
 		return
 	}
@@ -83,6 +103,28 @@ func (v *Voronoi) Generate() {
 	for v.EventQueue.Len() > 0 {
 		v.HandleNextEvent()
 	}
+
+	v.closeHull()
+	v.Clip()
+}
+
+// closeHull records an infinite triangle for every pair of neighbouring
+// arcs still standing once the sweep finishes, so the convex hull shows
+// up in the Delaunay dual alongside the bounded triangles.
+func (v *Voronoi) closeHull() {
+	if v.ParabolaTree == nil {
+		return
+	}
+
+	arc := v.ParabolaTree.FirstArc()
+	for arc != nil {
+		next := arc.NextArc()
+		if next == nil {
+			break
+		}
+		v.recordHullTriangle(arc.Site, next.Site)
+		arc = next
+	}
 }
 
 func (v *Voronoi) findNodeAbove(site *Site) *Node {
@@ -142,6 +184,14 @@ func (v *Voronoi) handleSiteEvent(event *Event) {
 	v.removeCircleEvent(arcAbove)
 
 	y := GetYByX(arcAbove.Site, event.Site.X, v.SweepLine)
+	// NewFromPointsF callers keep a sub-pixel fraction per site that
+	// GetYByX's int-only Site fields can't see; recompute the same
+	// breakpoint height from that fuller precision when it's available.
+	if len(v.siteOffsets) > 0 {
+		if precise, ok := v.preciseArcY(arcAbove.Site, float64(event.Site.X), float64(v.SweepLine)); ok {
+			y = int(precise + 0.5)
+		}
+	}
 	vertex := v.DCEL.NewVertex(event.Site.X, y)
 	log.Printf("Y of intersection = %d,%d\r\n", vertex.X, vertex.Y)
 
@@ -195,47 +245,21 @@ func (v *Voronoi) handleSiteEvent(event *Event) {
 	v.addCircleEvent(newArc, nextArc, nextNextArc)
 }
 
+// calcCircle resolves the circumcenter of the three arcs' sites using
+// their full sub-pixel precision (see preciseCoord) and only rounds to
+// the pixel grid at the very end, so that two sites a pixel apart still
+// produce a circle event on the correct side of the sweep line. See
+// circleMath for the epsilon-scaled division-by-zero bailouts.
 func (v *Voronoi) calcCircle(site1, site2, site3 *Site) (x int, y int, r int, err error) {
-	x = 0
-	y = 0
-	r = 0
-	err = nil
-
-	x1 := float64(site1.X)
-	y1 := float64(site1.Y)
-
-	x2 := float64(site2.X)
-	y2 := float64(site2.Y)
-
-	x3 := float64(site3.X)
-	y3 := float64(site3.Y)
-
-	determinant := (x2*y3 + x1*y2 + y1*x3) - (y1*x2 + y2*x3 + x1*y3)
-	if determinant < 0 {
-		log.Printf("Sites are in reversed order, so circle would be clockwise")
-		err = fmt.Errorf("circle is clockwise - sites %f,%f %f,%f %f,%f are in reversed order", x1, y1, x2, y2, x3, y3)
-		return
-	}
+	x1, y1 := v.preciseCoord(site1)
+	x2, y2 := v.preciseCoord(site2)
+	x3, y3 := v.preciseCoord(site3)
 
-	if x2-x1 == 0 || x3-x2 == 0 {
-		log.Printf("Ignoring circle, division by zero")
-		err = fmt.Errorf("no circle found connecting points %f,%f %f,%f and %f,%f", x1, y1, x2, y2, x3, y3)
-		return
-	}
-
-	mr := (y2 - y1) / (x2 - x1)
-	mt := (y3 - y2) / (x3 - x2)
-
-	if mr == mt || mr-mt == 0 || mr == 0 {
-		log.Printf("Ignoring circle, division by zero")
-		err = fmt.Errorf("no circle found connecting points %f,%f %f,%f and %f,%f", x1, y1, x2, y2, x3, y3)
+	cx, cy, cr, err := circleMath(x1, y1, x2, y2, x3, y3)
+	if err != nil {
 		return
 	}
 
-	cx := (mr*mt*(y3-y1) + mr*(x2+x3) - mt*(x1+x2)) / (2 * (mr - mt))
-	cy := (y1+y2)/2 - (cx-(x1+x2)/2)/mr
-	cr := math.Pow((math.Pow((x2-cx), 2) + math.Pow((y2-cy), 2)), 0.5)
-
 	x = int(cx + 0.5)
 	y = int(cy + 0.5)
 	r = int(cr + 0.5)
@@ -295,6 +319,7 @@ func (v *Voronoi) handleCircleEvent(event *Event) {
 	log.Printf("Removing arc %v between %v and %v", event.Node, prevArc, nextArc)
 	log.Printf("Previous arc: %v", prevArc)
 	log.Printf("Next arc: %v", nextArc)
+	v.recordTriangle(prevArc.Site, event.Node.Site, nextArc.Site)
 	v.removeArc(event.Node)
 
 	v.removeAllCircleEvents(event.Node)
@@ -0,0 +1,309 @@
+package voronoi
+
+import (
+	"image"
+	"sort"
+
+	"github.com/quasoft/dcel"
+)
+
+// boundarySide identifies which side of v.Bounds a clipped edge exits
+// through, so Clip can stitch dangling endpoints in perimeter order.
+type boundarySide int
+
+const (
+	sideTop boundarySide = iota
+	sideRight
+	sideBottom
+	sideLeft
+)
+
+// boundaryStop is one place where an open half-edge was cut short by
+// v.Bounds. face is the cell that the edge bounds on the inside of the
+// rectangle, and vertex is the DCEL vertex already created at point, so
+// stitchBoundary can wire it in as an edge endpoint instead of minting
+// a fresh, disconnected one.
+type boundaryStop struct {
+	point  image.Point
+	vertex *dcel.Vertex
+	side   boundarySide
+	order  int // position along the side, increasing in CW perimeter order
+	face   *dcel.Face
+}
+
+// Clip closes every half-edge that Fortune's sweep left dangling off the
+// edge of v.Bounds. It extends each open breakpoint along its current
+// bisector direction until it first leaves the bounding rectangle,
+// drops a vertex there, and then stitches the resulting boundary
+// vertices (and the rectangle's corners) together side by side so every
+// dcel.Face ends up as a closed CCW polygon.
+//
+// Generate calls Clip once the sweep has finished; callers normally
+// don't need to invoke it directly.
+func (v *Voronoi) Clip() {
+	if v.ParabolaTree == nil {
+		return
+	}
+
+	var stops []boundaryStop
+
+	arc := v.ParabolaTree.FirstArc()
+	for arc != nil {
+		next := arc.NextArc()
+		if next == nil {
+			break
+		}
+
+		edge := lastEdge(arc.RightEdges)
+		if edge == nil {
+			edge = lastEdge(next.LeftEdges)
+		}
+		if edge != nil {
+			stops = append(stops, v.clipBreakpoint(arc, next, edge)...)
+		}
+
+		arc = next
+	}
+
+	v.stitchBoundary(stops)
+}
+
+func lastEdge(edges []*dcel.HalfEdge) *dcel.HalfEdge {
+	if len(edges) == 0 {
+		return nil
+	}
+	return edges[len(edges)-1]
+}
+
+// clipBreakpoint extends the still-open edge between two neighbouring
+// arcs to where it exits v.Bounds and terminates it there. It returns
+// the single boundary stop created (a corner hit is one stop, not two),
+// or nil if the breakpoint or its exit point can't be computed.
+func (v *Voronoi) clipBreakpoint(left, right *Node, edge *dcel.HalfEdge) []boundaryStop {
+	// Reuse the breakpoint math findNodeAbove relies on by wrapping the
+	// two arcs in a throwaway internal node.
+	breakpoint := &Node{Left: left, Right: right}
+
+	x, err := GetXOfInternalNode(breakpoint, v.SweepLine)
+	if err != nil {
+		return nil
+	}
+	y := GetYByX(left.Site, x, v.SweepLine)
+
+	// A second point further down the same bisector gives us a
+	// direction to ray-cast against the bounding rectangle.
+	farY := v.SweepLine + v.Bounds.Dy() + v.Bounds.Dx() + 1
+	farX, err := GetXOfInternalNode(breakpoint, farY)
+	if err != nil {
+		return nil
+	}
+
+	hit, side, ok := intersectRay(image.Pt(x, y), image.Pt(farX, GetYByX(left.Site, farX, farY)), v.Bounds)
+	if !ok {
+		return nil
+	}
+
+	vertex := v.DCEL.NewVertex(hit.X, hit.Y)
+	v.CloseTwins([]*dcel.HalfEdge{edge}, vertex)
+
+	return []boundaryStop{{
+		point:  hit,
+		vertex: vertex,
+		side:   side,
+		order:  perimeterOrder(hit, side, v.Bounds),
+		face:   left.Site.Face,
+	}}
+}
+
+// intersectRay finds where the ray from -> through passes through the
+// boundary of bounds, assuming from is inside it.
+func intersectRay(from, through image.Point, bounds image.Rectangle) (image.Point, boundarySide, bool) {
+	dx := through.X - from.X
+	dy := through.Y - from.Y
+	if dx == 0 && dy == 0 {
+		return image.Point{}, 0, false
+	}
+
+	best := -1.0
+	var bestPt image.Point
+	var bestSide boundarySide
+	found := false
+
+	consider := func(t float64, pt image.Point, side boundarySide) {
+		if t < 0 {
+			return
+		}
+		if !found || t < best {
+			found = true
+			best = t
+			bestPt = pt
+			bestSide = side
+		}
+	}
+
+	if dx != 0 {
+		if t := float64(bounds.Min.X-from.X) / float64(dx); t >= 0 {
+			y := from.Y + int(float64(dy)*t)
+			if y >= bounds.Min.Y && y <= bounds.Max.Y {
+				consider(t, image.Pt(bounds.Min.X, y), sideLeft)
+			}
+		}
+		if t := float64(bounds.Max.X-from.X) / float64(dx); t >= 0 {
+			y := from.Y + int(float64(dy)*t)
+			if y >= bounds.Min.Y && y <= bounds.Max.Y {
+				consider(t, image.Pt(bounds.Max.X, y), sideRight)
+			}
+		}
+	}
+	if dy != 0 {
+		if t := float64(bounds.Min.Y-from.Y) / float64(dy); t >= 0 {
+			x := from.X + int(float64(dx)*t)
+			if x >= bounds.Min.X && x <= bounds.Max.X {
+				consider(t, image.Pt(x, bounds.Min.Y), sideTop)
+			}
+		}
+		if t := float64(bounds.Max.Y-from.Y) / float64(dy); t >= 0 {
+			x := from.X + int(float64(dx)*t)
+			if x >= bounds.Min.X && x <= bounds.Max.X {
+				consider(t, image.Pt(x, bounds.Max.Y), sideBottom)
+			}
+		}
+	}
+
+	return bestPt, bestSide, found
+}
+
+// perimeterOrder gives a monotonically increasing key for a point on a
+// given side of bounds, walking the rectangle clockwise starting at the
+// top-left corner. It's only ever compared against other points on the
+// same side.
+func perimeterOrder(p image.Point, side boundarySide, bounds image.Rectangle) int {
+	switch side {
+	case sideTop:
+		return p.X - bounds.Min.X
+	case sideRight:
+		return p.Y - bounds.Min.Y
+	case sideBottom:
+		return bounds.Max.X - p.X
+	default: // sideLeft
+		return bounds.Max.Y - p.Y
+	}
+}
+
+// stitchBoundary walks the rectangle clockwise from the top-left
+// corner and glues every dangling endpoint it passes to its neighbour,
+// inserting synthetic half-edge pairs so each face closes into a ring.
+// Corners that no open edge landed on exactly become plain pass-through
+// vertices shared by whichever face is still open at that point.
+func (v *Voronoi) stitchBoundary(stops []boundaryStop) {
+	if len(stops) == 0 {
+		return
+	}
+
+	sort.Slice(stops, func(i, j int) bool {
+		if stops[i].side != stops[j].side {
+			return stops[i].side < stops[j].side
+		}
+		return stops[i].order < stops[j].order
+	})
+
+	corners := []image.Point{
+		v.Bounds.Min,
+		image.Pt(v.Bounds.Max.X, v.Bounds.Min.Y),
+		v.Bounds.Max,
+		image.Pt(v.Bounds.Min.X, v.Bounds.Max.Y),
+	}
+
+	for i := 0; i < len(stops); i++ {
+		cur := stops[i]
+		nxt := stops[(i+1)%len(stops)]
+		single := len(stops) == 1
+
+		// Walk every corner strictly between cur and nxt in CW order;
+		// they all belong to cur's face, since no other site's cell
+		// reaches the box edge until nxt. With a single stop the walk
+		// goes all the way around back to itself.
+		side := cur.side
+		for step := 0; step < 4; step++ {
+			if side == nxt.side && !single {
+				break
+			}
+
+			corner := corners[(side+1)%4]
+			if corner != cur.point {
+				cornerStop := boundaryStop{
+					point:  corner,
+					vertex: v.DCEL.NewVertex(corner.X, corner.Y),
+					side:   side,
+					face:   cur.face,
+				}
+				v.glue(cur, cornerStop)
+				cur = cornerStop
+			}
+
+			side = (side + 1) % 4
+			if side == nxt.side {
+				break
+			}
+		}
+
+		v.glue(cur, nxt)
+	}
+}
+
+// glue inserts one synthetic half-edge pair bordering from.face on the
+// inside of the rectangle and the implicit outer face on the outside,
+// originating at from's vertex and terminating at to's.
+func (v *Voronoi) glue(from, to boundaryStop) {
+	if from.point == to.point {
+		return
+	}
+	outer := v.outerFace()
+	edge, _ := v.DCEL.NewEdge(from.face, outer, from.vertex)
+	v.CloseTwins([]*dcel.HalfEdge{edge}, to.vertex)
+}
+
+// outerFace lazily creates the unbounded face representing the area
+// outside v.Bounds, so boundary edges have somewhere to point their
+// twin half-edge.
+func (v *Voronoi) outerFace() *dcel.Face {
+	if v.OuterFace == nil {
+		v.OuterFace = v.DCEL.NewFace()
+		v.OuterFace.ID = -1
+	}
+	return v.OuterFace
+}
+
+// Polygons returns the ordered ring of vertices bounding each site's
+// cell, keyed by site ID, walking the DCEL so callers don't have to.
+func (v *Voronoi) Polygons() map[int64][]image.Point {
+	result := make(map[int64][]image.Point)
+
+	for _, face := range v.DCEL.Faces {
+		site, ok := face.Data.(*Site)
+		if !ok || site == nil {
+			continue
+		}
+
+		start := face.HalfEdge
+		if start == nil {
+			continue
+		}
+
+		var ring []image.Point
+		edge := start
+		for {
+			if edge.Target != nil {
+				ring = append(ring, image.Pt(edge.Target.X, edge.Target.Y))
+			}
+			edge = edge.Next
+			if edge == nil || edge == start {
+				break
+			}
+		}
+
+		result[site.ID] = ring
+	}
+
+	return result
+}
@@ -0,0 +1,54 @@
+package voronoi
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPolygonsReturnClosedRingsForEverySite(t *testing.T) {
+	sites := []image.Point{
+		{X: 10, Y: 10},
+		{X: 90, Y: 10},
+		{X: 10, Y: 90},
+		{X: 90, Y: 90},
+	}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+
+	polygons := v.Polygons()
+	if len(polygons) != len(sites) {
+		t.Fatalf("got %d polygons, want %d", len(polygons), len(sites))
+	}
+
+	for id, ring := range polygons {
+		if len(ring) < 3 {
+			t.Errorf("site %d: ring has %d vertices, want at least 3", id, len(ring))
+			continue
+		}
+
+		for i, p := range ring {
+			next := ring[(i+1)%len(ring)]
+			if p == next {
+				t.Errorf("site %d: ring has a repeated vertex at %v, stitching collapsed an edge", id, p)
+			}
+		}
+
+		if area := shoelaceArea(ring); area <= 0 {
+			t.Errorf("site %d: ring encloses zero or negative area (%v), want a proper closed polygon", id, area)
+		}
+	}
+}
+
+// shoelaceArea returns twice the signed area of ring, which is zero
+// only if the ring is degenerate (e.g. every vertex collapsed onto the
+// same point).
+func shoelaceArea(ring []image.Point) int {
+	sum := 0
+	n := len(ring)
+	for i := 0; i < n; i++ {
+		p0 := ring[i]
+		p1 := ring[(i+1)%n]
+		sum += p0.X*p1.Y - p1.X*p0.Y
+	}
+	return sum
+}
@@ -0,0 +1,231 @@
+package voronoi
+
+import "image"
+
+// noNeighbor marks a Triangle edge that borders the convex hull instead
+// of another triangle.
+const noNeighbor = -1
+
+// noSite marks the unused third vertex of an infinite Triangle. It must
+// not collide with a real site ID, unlike the int64 zero value, which
+// is itself a valid ID.
+const noSite int64 = -1
+
+// Triangle is one face of the Delaunay dual, identified by the IDs of
+// its three sites. Infinite is set for the fan of triangles that close
+// off the unbounded cells; C is noSite for those, since there is no
+// third site, only the direction the hull edge opens towards.
+type Triangle struct {
+	A, B, C  int64
+	Infinite bool
+}
+
+// Triangulation is the dual graph Fortune's algorithm produces for
+// free: one Triangle per Voronoi vertex, with Neighbors[i][e] pointing
+// at the triangle across edge e of triangle i, or noNeighbor on the
+// convex hull.
+type Triangulation struct {
+	Triangles []Triangle
+	Neighbors [][3]int
+
+	sites     map[int64]*Site
+	edgeToTri map[[2]int64]int
+
+	// lastLocated caches the triangle found by the previous Locate call,
+	// so a series of spatially coherent queries (the common case) only
+	// ever walks the handful of hops between consecutive points instead
+	// of restarting from triangle 0 every time.
+	lastLocated int
+}
+
+// Edges returns every distinct edge of the triangulation as a pair of
+// site IDs, each pair reported once. Infinite triangles only contribute
+// their real A-B edge; B-C and C-A would just wire every hull site to
+// the noSite sentinel.
+func (t *Triangulation) Edges() [][2]int64 {
+	seen := make(map[[2]int64]bool)
+	var edges [][2]int64
+
+	addEdge := func(a, b int64) {
+		key := [2]int64{a, b}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		edges = append(edges, key)
+	}
+
+	for _, tri := range t.Triangles {
+		addEdge(tri.A, tri.B)
+		if tri.Infinite {
+			continue
+		}
+		addEdge(tri.B, tri.C)
+		addEdge(tri.C, tri.A)
+	}
+
+	return edges
+}
+
+// Locate walks the triangulation from the triangle returned by the
+// previous Locate call towards p, moving to whichever neighbor lies on
+// the wrong side of the edge being crossed. It returns the index of the
+// triangle containing p, or -1 if p falls outside the convex hull.
+// Average case is O(sqrt(n)) for a series of spatially coherent queries,
+// since each walk only needs to cross from the last hit triangle to the
+// next one; an isolated query far from the last hit can degrade towards
+// O(n).
+func (t *Triangulation) Locate(p image.Point) int {
+	if len(t.Triangles) == 0 {
+		return -1
+	}
+
+	current := t.lastLocated
+	if current < 0 || current >= len(t.Triangles) {
+		current = 0
+	}
+	// A triangulation this size is cheap to bound the walk against; a
+	// well-formed one never needs more than a handful of hops.
+	for range t.Triangles {
+		tri := t.Triangles[current]
+		if tri.Infinite {
+			// Stepping onto the hull fan means p lies outside the
+			// bounded triangulation in this direction.
+			return -1
+		}
+
+		a, b, c := t.sites[tri.A], t.sites[tri.B], t.sites[tri.C]
+		edges := [3][2]*Site{{a, b}, {b, c}, {c, a}}
+
+		moved := false
+		for i, e := range edges {
+			if crossProductSign(e[0], e[1], p) < 0 {
+				next := t.Neighbors[current][i]
+				if next == noNeighbor {
+					return -1
+				}
+				current = next
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			t.lastLocated = current
+			return current
+		}
+	}
+
+	return current
+}
+
+func crossProductSign(from, to *Site, p image.Point) int {
+	dx1, dy1 := to.X-from.X, to.Y-from.Y
+	dx2, dy2 := p.X-from.X, p.Y-from.Y
+	cross := dx1*dy2 - dy1*dx2
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Delaunay returns the dual Delaunay triangulation recorded while
+// Generate ran. Each bounded Voronoi vertex corresponds to exactly one
+// triangle of the three sites whose arcs met there; the unbounded cells
+// contribute infinite triangles closing off the convex hull so the hull
+// itself can be read off as their C-less edges.
+func (v *Voronoi) Delaunay() *Triangulation {
+	return v.triangulation
+}
+
+// recordTriangle is called from handleCircleEvent with the three sites
+// whose arcs converge on a new Voronoi vertex. It stitches the new
+// triangle's adjacency in by looking up, for each of its three edges,
+// the existing triangle (if any) that already shares that edge.
+func (v *Voronoi) recordTriangle(s1, s2, s3 *Site) {
+	if v.triangulation == nil {
+		v.triangulation = &Triangulation{
+			sites:     make(map[int64]*Site),
+			edgeToTri: make(map[[2]int64]int),
+		}
+	}
+	t := v.triangulation
+
+	t.sites[s1.ID] = s1
+	t.sites[s2.ID] = s2
+	t.sites[s3.ID] = s3
+
+	idx := len(t.Triangles)
+	t.Triangles = append(t.Triangles, Triangle{A: s1.ID, B: s2.ID, C: s3.ID})
+	t.Neighbors = append(t.Neighbors, [3]int{noNeighbor, noNeighbor, noNeighbor})
+
+	edges := [3][2]int64{{s1.ID, s2.ID}, {s2.ID, s3.ID}, {s3.ID, s1.ID}}
+	for _, e := range edges {
+		key := e
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if other, ok := t.edgeToTri[key]; ok {
+			linkNeighbors(t, idx, other, e)
+		} else {
+			t.edgeToTri[key] = idx
+		}
+	}
+}
+
+// recordHullTriangle is called once per pair of neighbouring arcs left
+// standing when the sweep finishes, closing the hull with an infinite
+// triangle over the edge between those two hull sites.
+func (v *Voronoi) recordHullTriangle(s1, s2 *Site) {
+	if v.triangulation == nil {
+		v.triangulation = &Triangulation{
+			sites:     make(map[int64]*Site),
+			edgeToTri: make(map[[2]int64]int),
+		}
+	}
+	t := v.triangulation
+
+	t.sites[s1.ID] = s1
+	t.sites[s2.ID] = s2
+
+	idx := len(t.Triangles)
+	t.Triangles = append(t.Triangles, Triangle{A: s1.ID, B: s2.ID, C: noSite, Infinite: true})
+	t.Neighbors = append(t.Neighbors, [3]int{noNeighbor, noNeighbor, noNeighbor})
+
+	key := [2]int64{s1.ID, s2.ID}
+	if key[0] > key[1] {
+		key[0], key[1] = key[1], key[0]
+	}
+	if other, ok := t.edgeToTri[key]; ok {
+		linkNeighbors(t, idx, other, [2]int64{s1.ID, s2.ID})
+	} else {
+		t.edgeToTri[key] = idx
+	}
+}
+
+func linkNeighbors(t *Triangulation, a, b int, edge [2]int64) {
+	edgeSlot := func(idx int) int {
+		tri := t.Triangles[idx]
+		verts := [3]int64{tri.A, tri.B, tri.C}
+		for i := 0; i < 3; i++ {
+			j := (i + 1) % 3
+			if (verts[i] == edge[0] && verts[j] == edge[1]) || (verts[i] == edge[1] && verts[j] == edge[0]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	if slot := edgeSlot(a); slot >= 0 {
+		t.Neighbors[a][slot] = b
+	}
+	if slot := edgeSlot(b); slot >= 0 {
+		t.Neighbors[b][slot] = a
+	}
+}
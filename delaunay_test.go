@@ -0,0 +1,62 @@
+package voronoi
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDelaunayEdgesSkipInfiniteSentinel(t *testing.T) {
+	sites := []image.Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 50, Y: 100}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+
+	tri := v.Delaunay()
+	if tri == nil {
+		t.Fatal("Delaunay() returned nil")
+	}
+
+	for _, edge := range tri.Edges() {
+		if edge[0] == noSite || edge[1] == noSite {
+			t.Errorf("edge %v references the noSite sentinel", edge)
+		}
+	}
+}
+
+func TestLocateFindsTriangleContainingInteriorPoint(t *testing.T) {
+	sites := []image.Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 50, Y: 100}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+
+	tri := v.Delaunay()
+
+	// Three sites produce exactly one bounded triangle (the three of
+	// them) plus three infinite hull triangles; the centroid must land
+	// in the bounded one.
+	idx := tri.Locate(image.Pt(50, 33))
+	if idx < 0 || idx >= len(tri.Triangles) {
+		t.Fatalf("Locate(50,33) = %d, want a valid triangle index", idx)
+	}
+
+	got := tri.Triangles[idx]
+	if got.Infinite {
+		t.Fatalf("Locate(50,33) landed on an infinite hull triangle %+v, want the bounded one", got)
+	}
+
+	ids := map[int64]bool{got.A: true, got.B: true, got.C: true}
+	for _, want := range []int64{0, 1, 2} {
+		if !ids[want] {
+			t.Errorf("bounded triangle %+v is missing site %d", got, want)
+		}
+	}
+}
+
+func TestLocateReturnsMinusOneOutsideHull(t *testing.T) {
+	sites := []image.Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 50, Y: 100}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+
+	tri := v.Delaunay()
+	if idx := tri.Locate(image.Pt(-1000, -1000)); idx != -1 {
+		t.Errorf("Locate() = %d for a point far outside the hull, want -1", idx)
+	}
+}
@@ -0,0 +1,179 @@
+package voronoi
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+)
+
+// geomEpsilon bounds the relative error calcCircle tolerates before
+// treating a determinant or slope difference as exactly zero.
+const geomEpsilon = 1e-9
+
+// sweepEpsilon bounds how far above the sweep line a popped event can
+// sit and still be honoured, absorbing the rounding calcCircle's
+// float64-to-int conversion introduces. calcCircle rounds both its
+// center Y and its radius to the nearest pixel (up to 0.5 each), so
+// bottomY = y+r can land up to a full pixel off; this must be at least
+// 1 or the int-valued event.Y can never actually fall inside the
+// window.
+const sweepEpsilon = 1.0
+
+// PointF is the float64 counterpart of image.Point, used at the public
+// boundary by NewFromPointsF so callers with sub-pixel site positions
+// don't have to round before building a diagram.
+type PointF struct {
+	X, Y float64
+}
+
+// RectF is the float64 counterpart of image.Rectangle.
+type RectF struct {
+	Min, Max PointF
+}
+
+// EpsEquals2D reports whether a and b are within eps of each other in
+// both dimensions, the same tolerance pattern HandleNextEvent applies
+// (one-dimensionally, via sweepEpsilon) to sweep-line positions.
+func EpsEquals2D(a, b PointF, eps float64) bool {
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	return dx >= -eps && dx <= eps && dy >= -eps && dy <= eps
+}
+
+// NewFromPointsF builds a diagram from sub-pixel site positions. Site
+// and DCEL storage are still pixel-quantized (the public boundary is
+// image.Point, same as NewFromPoints), but the fractional part of each
+// point that rounding would otherwise throw away is kept in
+// v.siteOffsets and fed back into both calcCircle and the breakpoint
+// math in handleSiteEvent (see preciseArcY), so circle events and the
+// Voronoi vertices they and site events produce are resolved at full
+// sub-pixel precision instead of from round-tripped integer
+// coordinates.
+func NewFromPointsF(points []PointF, bounds RectF) *Voronoi {
+	intPoints := make([]image.Point, len(points))
+	for i, p := range points {
+		intPoints[i] = image.Pt(round(p.X), round(p.Y))
+	}
+
+	voronoi := NewFromPoints(intPoints, rectFToRect(bounds))
+
+	voronoi.siteOffsets = make(map[int64]PointF, len(points))
+	for i, p := range points {
+		site := voronoi.Sites[i]
+		voronoi.siteOffsets[site.ID] = PointF{X: p.X - float64(site.X), Y: p.Y - float64(site.Y)}
+	}
+
+	return voronoi
+}
+
+func rectFToRect(r RectF) image.Rectangle {
+	return image.Rect(round(r.Min.X), round(r.Min.Y), round(r.Max.X), round(r.Max.Y))
+}
+
+func round(f float64) int {
+	if f < 0 {
+		return int(f - 0.5)
+	}
+	return int(f + 0.5)
+}
+
+// preciseCoord returns a site's position including any sub-pixel
+// fraction NewFromPointsF recorded for it, falling back to its plain
+// int coordinates for sites built through New/NewFromPoints.
+func (v *Voronoi) preciseCoord(site *Site) (x, y float64) {
+	x, y = float64(site.X), float64(site.Y)
+	if off, ok := v.siteOffsets[site.ID]; ok {
+		x += off.X
+		y += off.Y
+	}
+	return
+}
+
+// preciseArcY is the same "height of the beach-line parabola for site
+// at horizontal position x, with directrix at sweepY" computation
+// GetYByX performs for breakpoint vertices, but carried out in float64
+// on preciseCoord's sub-pixel coordinates instead of site's truncated
+// int fields. ok is false where the parabola degenerates (site exactly
+// on the sweep line), same case GetYByX has to special-case itself.
+func (v *Voronoi) preciseArcY(site *Site, x, sweepY float64) (y float64, ok bool) {
+	fx, fy := v.preciseCoord(site)
+	if fy == sweepY {
+		return 0, false
+	}
+	return ((x-fx)*(x-fx)/(fy-sweepY) + sweepY + fy) / 2, true
+}
+
+// VertexF recomputes, at full float64 precision, the circle center and
+// radius for the three sites that meet at a Voronoi vertex - the same
+// computation calcCircle performs before rounding it down to the pixel
+// the DCEL stores. Pass the three arcs' sites exactly as they were
+// passed to calcCircle (prevArc.Site, event.Node.Site, nextArc.Site at
+// a circle event).
+func (v *Voronoi) VertexF(site1, site2, site3 *Site) (center PointF, radius float64, err error) {
+	x1, y1 := v.preciseCoord(site1)
+	x2, y2 := v.preciseCoord(site2)
+	x3, y3 := v.preciseCoord(site3)
+
+	cx, cy, cr, err := circleMath(x1, y1, x2, y2, x3, y3)
+	if err != nil {
+		return PointF{}, 0, err
+	}
+	return PointF{X: cx, Y: cy}, cr, nil
+}
+
+// circleMath is the shared float64 circumcenter computation behind both
+// calcCircle (which rounds the result to the pixel grid for the DCEL)
+// and VertexF (which doesn't). The division-by-zero bailouts are scaled
+// by the magnitude of the coordinates involved rather than compared
+// against a bare zero, so near-collinear (but not exactly collinear)
+// sites still yield a valid circle instead of being silently dropped.
+func circleMath(x1, y1, x2, y2, x3, y3 float64) (cx, cy, cr float64, err error) {
+	scale := coordMagnitude(x1, y1, x2, y2, x3, y3)
+
+	determinant := (x2*y3 + x1*y2 + y1*x3) - (y1*x2 + y2*x3 + x1*y3)
+	if determinant < 0 {
+		log.Printf("Sites are in reversed order, so circle would be clockwise")
+		err = fmt.Errorf("circle is clockwise - sites %f,%f %f,%f %f,%f are in reversed order", x1, y1, x2, y2, x3, y3)
+		return
+	}
+
+	if nearZero(x2-x1, scale) || nearZero(x3-x2, scale) {
+		log.Printf("Ignoring circle, division by zero")
+		err = fmt.Errorf("no circle found connecting points %f,%f %f,%f and %f,%f", x1, y1, x2, y2, x3, y3)
+		return
+	}
+
+	mr := (y2 - y1) / (x2 - x1)
+	mt := (y3 - y2) / (x3 - x2)
+
+	if nearZero(mr-mt, scale) || nearZero(mr, scale) {
+		log.Printf("Ignoring circle, division by zero")
+		err = fmt.Errorf("no circle found connecting points %f,%f %f,%f and %f,%f", x1, y1, x2, y2, x3, y3)
+		return
+	}
+
+	cx = (mr*mt*(y3-y1) + mr*(x2+x3) - mt*(x1+x2)) / (2 * (mr - mt))
+	cy = (y1+y2)/2 - (cx-(x1+x2)/2)/mr
+	cr = math.Sqrt(math.Pow(x2-cx, 2) + math.Pow(y2-cy, 2))
+
+	return
+}
+
+// coordMagnitude returns a representative scale for a set of
+// coordinates, used to turn absolute zero-checks into relative ones.
+func coordMagnitude(coords ...float64) float64 {
+	max := 1.0
+	for _, c := range coords {
+		if abs := math.Abs(c); abs > max {
+			max = abs
+		}
+	}
+	return max
+}
+
+// nearZero reports whether v is within geomEpsilon of zero, relative to
+// scale, instead of requiring it to be exactly zero.
+func nearZero(v, scale float64) bool {
+	return math.Abs(v) <= geomEpsilon*scale
+}
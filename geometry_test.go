@@ -0,0 +1,36 @@
+package voronoi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewFromPointsFPreservesSubPixelPrecision(t *testing.T) {
+	points := []PointF{
+		{X: 10.3, Y: 10.7},
+		{X: 90.1, Y: 10.4},
+		{X: 50.6, Y: 90.2},
+	}
+	bounds := RectF{Min: PointF{X: 0, Y: 0}, Max: PointF{X: 100, Y: 100}}
+
+	v := NewFromPointsF(points, bounds)
+
+	for i := range v.Sites {
+		x, y := v.preciseCoord(&v.Sites[i])
+		if math.Abs(x-points[i].X) > 1e-9 || math.Abs(y-points[i].Y) > 1e-9 {
+			t.Errorf("site %d: preciseCoord = (%f,%f), want (%f,%f)", i, x, y, points[i].X, points[i].Y)
+		}
+	}
+}
+
+func TestEpsEquals2D(t *testing.T) {
+	a := PointF{X: 1, Y: 1}
+	b := PointF{X: 1.0004, Y: 0.9996}
+
+	if !EpsEquals2D(a, b, 1e-3) {
+		t.Errorf("expected a and b to compare equal within tolerance")
+	}
+	if EpsEquals2D(a, b, 1e-6) {
+		t.Errorf("expected a and b to compare unequal below tolerance")
+	}
+}
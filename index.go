@@ -0,0 +1,126 @@
+package voronoi
+
+import (
+	"image"
+
+	"github.com/tidwall/rtree"
+)
+
+// BuildIndex inserts every bounded face's polygon bounding box into one
+// in-memory R-tree (for SiteAt's containment test) and every site's own
+// center into a second one (for NearestSite/KNearest), so none of them
+// have to either rerun Fortune's algorithm or linearly scan every site.
+// It must be called again after Relax moves the sites, since the old
+// boxes and centers no longer match the regenerated diagram.
+func (v *Voronoi) BuildIndex() {
+	v.index = &rtree.RTree{}
+	v.centerIndex = &rtree.RTree{}
+	v.indexPolygons = v.Polygons()
+	v.indexSites = make(map[int64]*Site, len(v.Sites))
+
+	for i := range v.Sites {
+		site := &v.Sites[i]
+		v.indexSites[site.ID] = site
+
+		center := [2]float64{float64(site.X), float64(site.Y)}
+		v.centerIndex.Insert(center, center, site.ID)
+
+		ring := v.indexPolygons[site.ID]
+		if len(ring) == 0 {
+			continue
+		}
+
+		min, max := boundingBox(ring)
+		v.index.Insert(min, max, site.ID)
+	}
+}
+
+// boundingBox returns the min/max corners of ring as [2]float64 points,
+// the coordinate type tidwall/rtree expects.
+func boundingBox(ring []image.Point) ([2]float64, [2]float64) {
+	min := [2]float64{float64(ring[0].X), float64(ring[0].Y)}
+	max := min
+	for _, p := range ring[1:] {
+		if x := float64(p.X); x < min[0] {
+			min[0] = x
+		} else if x > max[0] {
+			max[0] = x
+		}
+		if y := float64(p.Y); y < min[1] {
+			min[1] = y
+		} else if y > max[1] {
+			max[1] = y
+		}
+	}
+	return min, max
+}
+
+// SiteAt returns the site whose cell contains p, or nil if p falls
+// outside every bounded cell. Call BuildIndex first.
+func (v *Voronoi) SiteAt(p image.Point) *Site {
+	if v.index == nil {
+		return nil
+	}
+
+	point := [2]float64{float64(p.X), float64(p.Y)}
+
+	var found *Site
+	v.index.Search(point, point, func(min, max [2]float64, data interface{}) bool {
+		id := data.(int64)
+		if pointInPolygon(p, v.indexPolygons[id]) {
+			found = v.indexSites[id]
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// NearestSite returns the site whose center is closest to p. Unlike
+// SiteAt it always returns a result (as long as the index isn't empty),
+// even for points outside v.Bounds.
+func (v *Voronoi) NearestSite(p image.Point) *Site {
+	nearest := v.KNearest(p, 1)
+	if len(nearest) == 0 {
+		return nil
+	}
+	return nearest[0]
+}
+
+// KNearest returns up to k sites whose centers are closest to p,
+// nearest first, using the R-tree's nearest-neighbor traversal instead
+// of sorting every site.
+func (v *Voronoi) KNearest(p image.Point, k int) []*Site {
+	if v.centerIndex == nil || k <= 0 {
+		return nil
+	}
+
+	point := [2]float64{float64(p.X), float64(p.Y)}
+
+	var result []*Site
+	v.centerIndex.Nearby(rtree.BoxDist[float64, any](point, point, nil), func(min, max [2]float64, data interface{}, dist float64) bool {
+		id := data.(int64)
+		result = append(result, v.indexSites[id])
+		return len(result) < k
+	})
+
+	return result
+}
+
+// pointInPolygon is a standard even-odd ray cast, used to disambiguate
+// R-tree candidates whose bounding boxes overlap.
+func pointInPolygon(p image.Point, ring []image.Point) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pi, pj := ring[i], ring[j]
+		if (pi.Y > p.Y) != (pj.Y > p.Y) {
+			x := pi.X + (p.Y-pi.Y)*(pj.X-pi.X)/(pj.Y-pi.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
@@ -0,0 +1,76 @@
+package voronoi
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestKNearestRanksByCenterNotCellBoundingBox(t *testing.T) {
+	sites := []image.Point{{X: 10, Y: 10}, {X: 95, Y: 95}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+	v.BuildIndex()
+
+	nearest := v.NearestSite(image.Pt(12, 12))
+	if nearest == nil {
+		t.Fatal("NearestSite returned nil")
+	}
+	if nearest.X != 10 || nearest.Y != 10 {
+		t.Errorf("NearestSite(12,12) = (%d,%d), want (10,10)", nearest.X, nearest.Y)
+	}
+}
+
+func TestKNearestOrdersAndTruncates(t *testing.T) {
+	sites := []image.Point{
+		{X: 5, Y: 5},
+		{X: 20, Y: 5},
+		{X: 50, Y: 50},
+		{X: 95, Y: 95},
+		{X: 5, Y: 95},
+	}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+	v.BuildIndex()
+
+	query := image.Pt(0, 0)
+	const k = 3
+	nearest := v.KNearest(query, k)
+
+	if len(nearest) != k {
+		t.Fatalf("KNearest(p, %d) returned %d sites, want %d", k, len(nearest), k)
+	}
+
+	dist := func(s *Site) float64 {
+		return math.Hypot(float64(s.X-query.X), float64(s.Y-query.Y))
+	}
+
+	for i := 1; i < len(nearest); i++ {
+		if dist(nearest[i-1]) > dist(nearest[i]) {
+			t.Errorf("KNearest not sorted nearest-first: site at index %d (%v) is farther than index %d (%v)",
+				i-1, nearest[i-1], i, nearest[i])
+		}
+	}
+
+	want := []image.Point{{X: 5, Y: 5}, {X: 20, Y: 5}, {X: 50, Y: 50}}
+	for i, w := range want {
+		if nearest[i].X != w.X || nearest[i].Y != w.Y {
+			t.Errorf("KNearest[%d] = (%d,%d), want (%d,%d)", i, nearest[i].X, nearest[i].Y, w.X, w.Y)
+		}
+	}
+}
+
+func TestSiteAtMatchesContainingCell(t *testing.T) {
+	sites := []image.Point{{X: 10, Y: 10}, {X: 90, Y: 90}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+	v.Generate()
+	v.BuildIndex()
+
+	site := v.SiteAt(image.Pt(12, 12))
+	if site == nil {
+		t.Fatal("SiteAt returned nil")
+	}
+	if site.X != 10 || site.Y != 10 {
+		t.Errorf("SiteAt(12,12) = (%d,%d), want (10,10)", site.X, site.Y)
+	}
+}
@@ -0,0 +1,119 @@
+package voronoi
+
+import (
+	"image"
+	"math"
+)
+
+// Relax runs iterations rounds of Lloyd's algorithm: regenerate the
+// diagram, move every site to the area-weighted centroid of its own
+// cell, and regenerate again. Site IDs are preserved across iterations
+// so callers can keep tracking individual cells (e.g. for stippling or
+// mesh generation) as they settle towards a centroidal tessellation. On
+// return, v.DCEL reflects the sites' final positions, so Polygons and
+// BuildIndex can be called immediately without a stale diagram.
+func (v *Voronoi) Relax(iterations int) {
+	for i := 0; i < iterations; i++ {
+		v.relaxOnce()
+	}
+	v.Generate()
+}
+
+// RelaxUntil runs Lloyd's algorithm until no site moves by more than
+// tolerance in a single round, or maxIter rounds have run, whichever
+// comes first. It returns the number of rounds actually performed. As
+// with Relax, v.DCEL is regenerated once more before returning so it
+// matches the sites' final positions.
+func (v *Voronoi) RelaxUntil(maxIter int, tolerance float64) int {
+	for i := 0; i < maxIter; i++ {
+		maxDisplacement := v.relaxOnce()
+		if maxDisplacement <= tolerance {
+			v.Generate()
+			return i + 1
+		}
+	}
+	v.Generate()
+	return maxIter
+}
+
+// relaxOnce regenerates the diagram, moves each site to its cell's
+// centroid, and reports the largest distance any site moved.
+func (v *Voronoi) relaxOnce() float64 {
+	v.Generate()
+
+	polygons := v.Polygons()
+
+	var maxDisplacement float64
+	for i, site := range v.Sites {
+		ring, ok := polygons[site.ID]
+		if !ok || len(ring) < 3 {
+			continue
+		}
+
+		cx, cy := polygonCentroid(ring)
+
+		sx, sy := v.preciseCoord(&site)
+		dx := cx - sx
+		dy := cy - sy
+		if d := math.Hypot(dx, dy); d > maxDisplacement {
+			maxDisplacement = d
+		}
+
+		newX := int(cx + 0.5)
+		newY := int(cy + 0.5)
+		v.Sites[i].X = newX
+		v.Sites[i].Y = newY
+
+		// Replace the stale sub-pixel offset from construction time (or
+		// from the previous round) with the centroid's own fraction, so
+		// preciseCoord keeps reflecting where the site actually is.
+		if v.siteOffsets != nil {
+			v.siteOffsets[site.ID] = PointF{X: cx - float64(newX), Y: cy - float64(newY)}
+		}
+	}
+
+	return maxDisplacement
+}
+
+// polygonCentroid computes the area-weighted centroid of a closed ring
+// via the shoelace-centroid formula:
+//
+//	Cx = (1/6A) * sum((x_i + x_{i+1}) * (x_i*y_{i+1} - x_{i+1}*y_i))
+//	Cy = (1/6A) * sum((y_i + y_{i+1}) * (x_i*y_{i+1} - x_{i+1}*y_i))
+//
+// ring is assumed to be closed implicitly (last point connects back to
+// the first). If the ring is degenerate (zero area), the plain vertex
+// average is returned instead.
+func polygonCentroid(ring []image.Point) (float64, float64) {
+	var area, cx, cy float64
+
+	n := len(ring)
+	for i := 0; i < n; i++ {
+		p0 := ring[i]
+		p1 := ring[(i+1)%n]
+
+		cross := float64(p0.X)*float64(p1.Y) - float64(p1.X)*float64(p0.Y)
+		area += cross
+		cx += (float64(p0.X) + float64(p1.X)) * cross
+		cy += (float64(p0.Y) + float64(p1.Y)) * cross
+	}
+
+	area /= 2
+	if math.Abs(area) < 1e-9 {
+		return averageVertices(ring)
+	}
+
+	cx /= 6 * area
+	cy /= 6 * area
+	return cx, cy
+}
+
+func averageVertices(ring []image.Point) (float64, float64) {
+	var sx, sy float64
+	for _, p := range ring {
+		sx += float64(p.X)
+		sy += float64(p.Y)
+	}
+	n := float64(len(ring))
+	return sx / n, sy / n
+}
@@ -0,0 +1,31 @@
+package voronoi
+
+import (
+	"image"
+	"testing"
+)
+
+func TestRelaxUntilKeepsSitesWithinBounds(t *testing.T) {
+	sites := []image.Point{{X: 10, Y: 50}, {X: 90, Y: 10}, {X: 90, Y: 90}}
+	v := NewFromPoints(sites, image.Rect(0, 0, 100, 100))
+
+	rounds := v.RelaxUntil(20, 0.5)
+	if rounds == 0 {
+		t.Fatal("RelaxUntil ran 0 rounds")
+	}
+
+	for _, site := range v.Sites {
+		if site.X < 0 || site.X > 100 || site.Y < 0 || site.Y > 100 {
+			t.Errorf("site %d relaxed outside Bounds: %+v", site.ID, site)
+		}
+	}
+}
+
+func TestPolygonCentroidOfASquareIsItsCenter(t *testing.T) {
+	square := []image.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	cx, cy := polygonCentroid(square)
+	if cx != 5 || cy != 5 {
+		t.Errorf("polygonCentroid(square) = (%v,%v), want (5,5)", cx, cy)
+	}
+}